@@ -8,6 +8,7 @@ import (
 	"hash"
 	"math"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
@@ -30,6 +31,7 @@ import (
 type CallBundleArgs struct {
 	Txs                    []hexutil.Bytes       `json:"txs"`
 	BlockNumber            rpc.BlockNumber       `json:"blockNumber"`
+	MaxBlockNumber         rpc.BlockNumber       `json:"maxBlockNumber"`
 	StateBlockNumberOrHash rpc.BlockNumberOrHash `json:"stateBlockNumber"`
 	Coinbase               *string               `json:"coinbase"`
 	Timestamp              *inter.Timestamp      `json:"timestamp"`
@@ -37,28 +39,84 @@ type CallBundleArgs struct {
 	GasLimit               *uint64               `json:"gasLimit"`
 	Difficulty             *big.Int              `json:"difficulty"`
 	BaseFee                *big.Int              `json:"baseFee"`
+	RevertingTxHashes      []common.Hash         `json:"revertingTxHashes"`
+	MinTimestamp           *uint64               `json:"minTimestamp"`
+	MaxTimestamp           *uint64               `json:"maxTimestamp"`
+	StateOverrides         *StateOverride        `json:"stateOverrides"`
+	BlockOverrides         *BlockOverrides       `json:"blockOverrides"`
+	Tracer                 *string               `json:"tracer"`
+	ParentBeaconBlockRoot  *common.Hash          `json:"parentBeaconBlockRoot"`
+}
+
+// SendBundleArgs 表示提交 bundle 的参数，字段含义与 CallBundleArgs 中同名字段一致
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       rpc.BlockNumber `json:"blockNumber"`
+	MaxBlockNumber    rpc.BlockNumber `json:"maxBlockNumber"`
+	MinTimestamp      *uint64         `json:"minTimestamp"`
+	MaxTimestamp      *uint64         `json:"maxTimestamp"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
 }
 
 // BundleAPI 提供 bundle 相关的 API
 type BundleAPI struct {
-	b Backend
+	b          Backend
+	pool       *BundlePool
+	reputation *ReputationTracker
 }
 
 // NewBundleAPI 创建一个新的 BundleAPI 实例
 func NewBundleAPI(b Backend) *BundleAPI {
+	// 在节点的 RPC server 用 NewBundleRPCHandler（见 bundle_signature.go）包裹其
+	// HTTP handler 之前，SearcherFromContext 永远取不到已验证的 searcher，
+	// SendBundle 会因缺少 X-Bundle-Signature 而拒绝所有请求。
+	log.Warn("BundleAPI created; eth_sendBundle requires the node's RPC HTTP handler to be wrapped with ethapi.NewBundleRPCHandler or every call will be rejected for a missing X-Bundle-Signature header")
 	return &BundleAPI{
-		b: b,
+		b:          b,
+		pool:       NewBundlePool(),
+		reputation: NewReputationTracker(),
+	}
+}
+
+// SearcherStats 返回 searcher（通过 X-Bundle-Signature 验证）的 landed/failed
+// bundle 计数及最近一次的有效 gas price。
+func (s *BundleAPI) SearcherStats(searcher common.Address) SearcherReputation {
+	return s.reputation.Stats(searcher)
+}
+
+// isAllowedToRevert 判断给定交易是否在 bundle 的 revert 允许列表中
+func isAllowedToRevert(txHash common.Hash, revertingTxHashes []common.Hash) bool {
+	for _, h := range revertingTxHashes {
+		if h == txHash {
+			return true
+		}
 	}
+	return false
 }
 
 // CallBundle 将在给定区块号顶部模拟一组交易
-func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[string]interface{}, error) {
+func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (result map[string]interface{}, err error) {
 	if len(args.Txs) == 0 {
 		return nil, errors.New("bundle missing txs")
 	}
 	if args.BlockNumber == 0 {
 		return nil, errors.New("bundle missing blockNumber")
 	}
+	if args.MaxBlockNumber != 0 && args.MaxBlockNumber < args.BlockNumber {
+		return nil, errors.New("maxBlockNumber must not be lower than blockNumber")
+	}
+
+	searcher, hasSearcher := SearcherFromContext(ctx)
+	if hasSearcher && !s.reputation.Allow(searcher) {
+		return nil, fmt.Errorf("searcher %s is rate-limited due to repeated failed bundles", searcher)
+	}
+	if hasSearcher {
+		defer func() {
+			if err != nil {
+				s.reputation.RecordFailed(searcher)
+			}
+		}()
+	}
 
 	var txs types.Transactions
 	for _, encodedTx := range args.Txs {
@@ -91,6 +149,11 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 	if state == nil || err != nil {
 		return nil, err
 	}
+	if args.StateOverrides != nil {
+		if err := args.StateOverrides.Apply(state); err != nil {
+			return nil, err
+		}
+	}
 
 	blockNumber := big.NewInt(int64(args.BlockNumber))
 
@@ -98,6 +161,12 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 	if args.Timestamp != nil {
 		timestamp = *args.Timestamp
 	}
+	if args.MinTimestamp != nil && uint64(timestamp) < *args.MinTimestamp {
+		return nil, fmt.Errorf("timestamp %d is below minTimestamp %d", timestamp, *args.MinTimestamp)
+	}
+	if args.MaxTimestamp != nil && uint64(timestamp) > *args.MaxTimestamp {
+		return nil, fmt.Errorf("timestamp %d is above maxTimestamp %d", timestamp, *args.MaxTimestamp)
+	}
 
 	coinbase := parent.Coinbase
 	if args.Coinbase != nil {
@@ -117,7 +186,6 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 	}
 
 	header := &evmcore.EvmHeader{
-		// 下面一行有错误
 		ParentHash: parent.Hash,
 		Number:     blockNumber,
 		GasLimit:   gasLimit,
@@ -125,14 +193,29 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 		Coinbase:   coinbase,
 		BaseFee:    baseFee,
 	}
+	// BlockOverrides 可能替换 header.Number/header.Time，所以 Cancun 是否生效
+	// 必须由覆盖后的 header 决定，而不是覆盖前的原始值，否则 blob gas 的计算
+	// 会与 bundle 实际模拟所在的区块不一致。
+	args.BlockOverrides.Apply(header)
+
+	isCancun := s.b.ChainConfig().IsCancun(header.Number, uint64(header.Time))
+	if isCancun {
+		applyBlobHeaderFields(header, parent.EthHeader(), args.ParentBeaconBlockRoot)
+		if args.BlockOverrides != nil && args.BlockOverrides.BlobBaseFee != nil {
+			header.BlobBaseFee = args.BlockOverrides.BlobBaseFee.ToInt()
+		}
+	}
 
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	blobPool := newBlobGasPool()
 
 	results := []map[string]interface{}{}
 	coinbaseBalanceBefore := state.GetBalance(coinbase)
 
 	bundleHash := sha3.NewLegacyKeccak256()
-	signer := types.MakeSigner(s.b.ChainConfig(), blockNumber, header.EthHeader().Time)
+	// 一旦上面应用了 BlockOverrides，真正执行 bundle 所依据的区块就是
+	// header.Number/header.Time，而不是覆盖前的 blockNumber/timestamp。
+	signer := types.MakeSigner(s.b.ChainConfig(), header.Number, header.EthHeader().Time)
 	var totalGasUsed uint64
 	gasFees := new(big.Int)
 
@@ -141,10 +224,18 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 			return nil, err
 		}
 
+		if err := accountBlobGas(tx, isCancun, header, blobPool); err != nil {
+			return nil, err
+		}
+
 		coinbaseBalanceBeforeTx := state.GetBalance(coinbase)
 		state.SetTxContext(tx.Hash(), i)
 
-		evm, msg, err := GetEVM(ctx, s.b, state, header, tx)
+		vmConfig, tracer, err := NewTracerConfig(args.Tracer, tx.Hash(), parent.Hash, header.Number.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		evm, msg, err := GetEVMWithConfig(ctx, s.b, state, header, tx, vmConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -152,18 +243,36 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 		if err != nil {
 			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
 		}
+		if result.Err != nil && !isAllowedToRevert(tx.Hash(), args.RevertingTxHashes) {
+			return nil, fmt.Errorf("tx %s reverted and is not in the allowed revert list: %w", tx.Hash(), result.Err)
+		}
 
 		// 处理交易结果
 		jsonResult := s.processTransactionResult(tx, receipt, result, signer, header.EthHeader(), coinbaseBalanceBeforeTx, state)
-		totalGasUsed += receipt.GasUsed
-
-		gasPrice, err := tx.EffectiveGasTip(header.BaseFee)
-		if err != nil {
+		if trace, err := TraceResult(tracer); err != nil {
 			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		} else if trace != nil {
+			jsonResult["trace"] = trace
 		}
+		if receipt.BlobGasUsed > 0 {
+			jsonResult["blobGasUsed"] = receipt.BlobGasUsed
+			jsonResult["blobGasPrice"] = receipt.BlobGasPrice.String()
+		}
+		if delegations := setCodeDelegations(tx); len(delegations) > 0 {
+			jsonResult["delegations"] = delegations
+		}
+
+		if result.Err == nil {
+			totalGasUsed += receipt.GasUsed
 
-		gasFeesTx := new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), gasPrice)
-		gasFees.Add(gasFees, gasFeesTx)
+			gasPrice, err := tx.EffectiveGasTip(header.BaseFee)
+			if err != nil {
+				return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+			}
+
+			gasFeesTx := new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), gasPrice)
+			gasFees.Add(gasFees, gasFeesTx)
+		}
 		bundleHash.Write(tx.Hash().Bytes())
 
 		results = append(results, jsonResult)
@@ -172,6 +281,281 @@ func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (map[st
 	return s.prepareFinalResult(results, state, coinbaseBalanceBefore, coinbase, gasFees, totalGasUsed, parent.EthHeader(), bundleHash), nil
 }
 
+// SendBundle 将一个已签名的 bundle 提交给 BundlePool，等待 miner/emitter 按 gas price 顺序打包
+func (s *BundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	searcher, ok := SearcherFromContext(ctx)
+	if !ok {
+		return common.Hash{}, errors.New("bundle missing a valid X-Bundle-Signature header")
+	}
+	if !s.reputation.Allow(searcher) {
+		return common.Hash{}, fmt.Errorf("searcher %s is rate-limited due to repeated failed bundles", searcher)
+	}
+	if len(args.Txs) == 0 {
+		return common.Hash{}, errors.New("bundle missing txs")
+	}
+	if args.BlockNumber == 0 {
+		return common.Hash{}, errors.New("bundle missing blockNumber")
+	}
+	if args.MaxBlockNumber != 0 && args.MaxBlockNumber < args.BlockNumber {
+		return common.Hash{}, errors.New("maxBlockNumber must not be lower than blockNumber")
+	}
+
+	var txs types.Transactions
+	for _, encodedTx := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encodedTx); err != nil {
+			return common.Hash{}, err
+		}
+		txs = append(txs, tx)
+	}
+
+	bundle := &Bundle{
+		Txs:               txs,
+		BlockNumber:       args.BlockNumber,
+		MaxBlockNumber:    args.MaxBlockNumber,
+		MinTimestamp:      args.MinTimestamp,
+		MaxTimestamp:      args.MaxTimestamp,
+		RevertingTxHashes: args.RevertingTxHashes,
+		Searcher:          searcher,
+	}
+
+	gasPrice, err := s.estimateBundleGasPrice(ctx, bundle)
+	if err != nil {
+		s.reputation.RecordFailed(searcher)
+		return common.Hash{}, err
+	}
+	bundle.GasPrice = gasPrice
+
+	hash, err := s.pool.Add(bundle)
+	if err != nil {
+		s.reputation.RecordFailed(searcher)
+		return common.Hash{}, err
+	}
+	// landed 计数要等 block-building 路径（PrependPendingBundles）取出该 bundle
+	// 时才会更新；failed 计数只在提交或模拟失败时记录，这里入队成功不更新任何计数。
+	warnBundlesUnwiredOnce.Do(func() {
+		log.Warn("Bundle queued via SendBundle; a block-building path must call AssembleBlockTxs (over RPC) or PrependPendingBundles (in-process) while assembling a block, or queued bundles will never be included")
+	})
+	return hash, nil
+}
+
+// warnBundlesUnwiredOnce 每个进程最多打印一次，提示 SendBundle 入队的 bundle
+// 只有在真正被调用了 AssembleBlockTxs/PrependPendingBundles 时才会被消费。
+var warnBundlesUnwiredOnce sync.Once
+
+// PrependPendingBundles 是 block-building 路径在进程内组装
+// blockNumber/timestamp 对应区块时调用的钩子：它按 gas price 从高到低把
+// s.pool 中对该区块仍然有效的 bundle 取出，将其交易前插到 txs 之前，并把每个
+// 被取出 bundle 的 searcher 记为 landed，使后续 SearcherStats 反映被取出打包
+// 的 bundle，而不仅仅是被提交过的。限流判断（ReputationTracker.Allow）只看
+// failed 计数，不受 landed 计数影响。
+//
+// 本包没有进程内的 block-building/emitter 代码可以直接调用它，所以下面的
+// AssembleBlockTxs 把同样的操作以 JSON-RPC 方法的形式暴露出去，供树外的
+// block-building 进程调用。
+func (s *BundleAPI) PrependPendingBundles(blockNumber uint64, timestamp uint64, txs types.Transactions) types.Transactions {
+	pending := s.pool.Pending(blockNumber, timestamp)
+	for _, bundle := range pending {
+		s.reputation.RecordLanded(bundle.Searcher, bundle.GasPrice)
+	}
+	return append(flatten(pending), txs...)
+}
+
+// AssembleBlockTxs 把 PrependPendingBundles 以 JSON-RPC 方法的形式暴露出去
+//（见 NewBundleRPCServer），供树外的 block-building 路径在组装
+// blockNumber/timestamp 对应区块时调用：txs 是该路径本来打算打包的一组已编码
+// 交易，返回值是同一组交易，并把池中待处理的 bundle 按 gas price 从高到低
+// 前插在它们之前。
+func (s *BundleAPI) AssembleBlockTxs(blockNumber uint64, timestamp uint64, txs []hexutil.Bytes) ([]hexutil.Bytes, error) {
+	decoded := make(types.Transactions, len(txs))
+	for i, encodedTx := range txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encodedTx); err != nil {
+			return nil, err
+		}
+		decoded[i] = tx
+	}
+
+	merged := s.PrependPendingBundles(blockNumber, timestamp, decoded)
+
+	encodedMerged := make([]hexutil.Bytes, len(merged))
+	for i, tx := range merged {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		encodedMerged[i] = encoded
+	}
+	return encodedMerged, nil
+}
+
+// estimateBundleGasPrice 用和 CallBundle 相同的方式模拟 bundle，得到用于
+// BundlePool 排序/淘汰的有效 gas price。这次模拟由 SendBundle 内部发起，
+// 而 SendBundle 自己在失败时已经记了一次 searcher 失败，所以这里调用
+// CallBundle 时要把 context 里的 searcher 身份剥离，避免被 CallBundle 自己
+// 的 deferred 钩子重复计数。
+func (s *BundleAPI) estimateBundleGasPrice(ctx context.Context, bundle *Bundle) (*big.Int, error) {
+	encodedTxs := make([]hexutil.Bytes, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		encodedTxs[i] = encoded
+	}
+
+	result, err := s.CallBundle(withoutSearcher(ctx), CallBundleArgs{
+		Txs:                    encodedTxs,
+		BlockNumber:            bundle.BlockNumber,
+		MaxBlockNumber:         bundle.MaxBlockNumber,
+		StateBlockNumberOrHash: rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber),
+		MinTimestamp:           bundle.MinTimestamp,
+		MaxTimestamp:           bundle.MaxTimestamp,
+		RevertingTxHashes:      bundle.RevertingTxHashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, ok := new(big.Int).SetString(result["bundleGasPrice"].(string), 10)
+	if !ok {
+		return nil, errors.New("failed to parse bundleGasPrice")
+	}
+	return gasPrice, nil
+}
+
+// EstimateGasBundle 对 bundle 做一次 dry-run，只统计每笔交易的 gas 消耗，不计算 coinbase diff
+func (s *BundleAPI) EstimateGasBundle(ctx context.Context, args CallBundleArgs) (map[string]interface{}, error) {
+	if len(args.Txs) == 0 {
+		return nil, errors.New("bundle missing txs")
+	}
+	if args.BlockNumber == 0 {
+		return nil, errors.New("bundle missing blockNumber")
+	}
+	if args.MaxBlockNumber != 0 && args.MaxBlockNumber < args.BlockNumber {
+		return nil, errors.New("maxBlockNumber must not be lower than blockNumber")
+	}
+
+	var txs types.Transactions
+	for _, encodedTx := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encodedTx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+
+	state, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, args.StateBlockNumberOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if args.StateOverrides != nil {
+		if err := args.StateOverrides.Apply(state); err != nil {
+			return nil, err
+		}
+	}
+
+	blockNumber := big.NewInt(int64(args.BlockNumber))
+
+	timestamp := parent.Time + 1
+	if args.Timestamp != nil {
+		timestamp = *args.Timestamp
+	}
+	if args.MinTimestamp != nil && uint64(timestamp) < *args.MinTimestamp {
+		return nil, fmt.Errorf("timestamp %d is below minTimestamp %d", timestamp, *args.MinTimestamp)
+	}
+	if args.MaxTimestamp != nil && uint64(timestamp) > *args.MaxTimestamp {
+		return nil, fmt.Errorf("timestamp %d is above maxTimestamp %d", timestamp, *args.MaxTimestamp)
+	}
+
+	gasLimit := parent.GasLimit
+	if args.GasLimit != nil {
+		gasLimit = *args.GasLimit
+	}
+
+	var baseFee *big.Int
+	if args.BaseFee != nil {
+		baseFee = args.BaseFee
+	} else if s.b.ChainConfig().IsLondon(big.NewInt(args.BlockNumber.Int64())) {
+		baseFee = eip1559.CalcBaseFee(s.b.ChainConfig(), parent.EthHeader())
+	}
+
+	header := &evmcore.EvmHeader{
+		ParentHash: parent.Hash,
+		Number:     blockNumber,
+		GasLimit:   gasLimit,
+		Time:       timestamp,
+		Coinbase:   parent.Coinbase,
+		BaseFee:    baseFee,
+	}
+	// BlockOverrides 可能替换 header.Number/header.Time，所以 Cancun 是否生效
+	// 必须由覆盖后的 header 决定，而不是覆盖前的原始值，否则 blob gas 的计算
+	// 会与 bundle 实际模拟所在的区块不一致。
+	args.BlockOverrides.Apply(header)
+
+	isCancun := s.b.ChainConfig().IsCancun(header.Number, uint64(header.Time))
+	if isCancun {
+		applyBlobHeaderFields(header, parent.EthHeader(), args.ParentBeaconBlockRoot)
+		if args.BlockOverrides != nil && args.BlockOverrides.BlobBaseFee != nil {
+			header.BlobBaseFee = args.BlockOverrides.BlobBaseFee.ToInt()
+		}
+	}
+
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	blobPool := newBlobGasPool()
+	results := []map[string]interface{}{}
+	var totalGasUsed uint64
+
+	for i, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := accountBlobGas(tx, isCancun, header, blobPool); err != nil {
+			return nil, err
+		}
+
+		state.SetTxContext(tx.Hash(), i)
+
+		vmConfig, tracer, err := NewTracerConfig(args.Tracer, tx.Hash(), parent.Hash, header.Number.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		evm, msg, err := GetEVMWithConfig(ctx, s.b, state, header, tx, vmConfig)
+		if err != nil {
+			return nil, err
+		}
+		receipt, result, err := evmcore.ApplyTransactionWithResult(msg, gp, state, header, tx, &header.GasUsed, evm)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+		if result.Err != nil && !isAllowedToRevert(tx.Hash(), args.RevertingTxHashes) {
+			return nil, fmt.Errorf("tx %s reverted and is not in the allowed revert list: %w", tx.Hash(), result.Err)
+		}
+
+		jsonResult := map[string]interface{}{
+			"txHash":  tx.Hash().String(),
+			"gasUsed": receipt.GasUsed,
+		}
+		if result.Err != nil {
+			jsonResult["error"] = result.Err.Error()
+		}
+		if trace, err := TraceResult(tracer); err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		} else if trace != nil {
+			jsonResult["trace"] = trace
+		}
+
+		totalGasUsed += receipt.GasUsed
+		results = append(results, jsonResult)
+	}
+
+	return map[string]interface{}{
+		"results":      results,
+		"totalGasUsed": totalGasUsed,
+	}, nil
+}
+
 // processTransactionResult 处理单个交易的结果
 func (s *BundleAPI) processTransactionResult(tx *types.Transaction, receipt *types.Receipt, result *core.ExecutionResult, signer types.Signer, header *types.Header, coinbaseBalanceBeforeTx *uint256.Int, state vm.StateDB) map[string]interface{} {
 	txHash := tx.Hash().String()
@@ -223,7 +607,11 @@ func (s *BundleAPI) prepareFinalResult(results []map[string]interface{}, state v
 	ret["coinbaseDiff"] = coinbaseDiff.String()
 	ret["gasFees"] = gasFees.String()
 	ret["ethSentToCoinbase"] = new(big.Int).Sub(coinbaseDiff, gasFees).String()
-	ret["bundleGasPrice"] = new(big.Int).Div(coinbaseDiff, big.NewInt(int64(totalGasUsed))).String()
+	bundleGasPrice := new(big.Int)
+	if totalGasUsed > 0 {
+		bundleGasPrice = new(big.Int).Div(coinbaseDiff, big.NewInt(int64(totalGasUsed)))
+	}
+	ret["bundleGasPrice"] = bundleGasPrice.String()
 	ret["bundleHash"] = "0x" + common.Bytes2Hex(bundleHash.Sum(nil))
 
 	return ret
@@ -236,12 +624,17 @@ type EBackend interface {
 
 // apply transaction returning result, for callBundle
 func GetEVM(ctx context.Context, b EBackend, statedb state.StateDB, header *evmcore.EvmHeader, tx *types.Transaction) (*vm.EVM, *core.Message, error) {
+	return GetEVMWithConfig(ctx, b, statedb, header, tx, &vm.Config{})
+}
+
+// GetEVMWithConfig 与 GetEVM 类似，但允许调用方传入 vm.Config，
+// 例如为调试 bundle 的执行过程附加 tracer。
+func GetEVMWithConfig(ctx context.Context, b EBackend, statedb state.StateDB, header *evmcore.EvmHeader, tx *types.Transaction, vmConfig *vm.Config) (*vm.EVM, *core.Message, error) {
 	config := b.ChainConfig()
 	msg, err := core.TransactionToMessage(tx, types.MakeSigner(config, header.Number, uint64(header.Time)), header.BaseFee)
 	if err != nil {
 		return nil, nil, err
 	}
-	vmconfig := vm.Config{}
-	evm, _, err := b.GetEVM(ctx, msg, statedb, header, &vmconfig)
+	evm, _, err := b.GetEVM(ctx, msg, statedb, header, vmConfig)
 	return evm, msg, err
 }
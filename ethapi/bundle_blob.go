@@ -0,0 +1,103 @@
+package ethapi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// applyBlobHeaderFields fills in the EIP-4844 blob-gas fields of header from
+// parent, so a bundle simulated against a synthetic header accounts for blob
+// gas the same way a real block would.
+func applyBlobHeaderFields(header *evmcore.EvmHeader, parent *types.Header, parentBeaconBlockRoot *common.Hash) {
+	var parentExcessBlobGas, parentBlobGasUsed uint64
+	if parent.ExcessBlobGas != nil {
+		parentExcessBlobGas = *parent.ExcessBlobGas
+	}
+	if parent.BlobGasUsed != nil {
+		parentBlobGasUsed = *parent.BlobGasUsed
+	}
+
+	excessBlobGas := eip4844.CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed)
+	header.ExcessBlobGas = &excessBlobGas
+	header.BlobGasUsed = new(uint64)
+	header.BlobBaseFee = eip4844.CalcBlobFee(excessBlobGas)
+
+	if parentBeaconBlockRoot != nil {
+		header.ParentBeaconBlockRoot = parentBeaconBlockRoot
+	}
+}
+
+// blobGasPool bounds the total blob gas a bundle may consume within a single
+// synthetic block, mirroring the block-level gas pool used for execution gas.
+type blobGasPool struct {
+	remaining uint64
+}
+
+func newBlobGasPool() *blobGasPool {
+	return &blobGasPool{remaining: params.MaxBlobGasPerBlock}
+}
+
+// subGas deducts blobGas from the pool, returning an error if doing so would
+// exceed the per-block blob gas limit.
+func (p *blobGasPool) subGas(blobGas uint64) error {
+	if p.remaining < blobGas {
+		return fmt.Errorf("blob gas pool exhausted: have %d, want %d", p.remaining, blobGas)
+	}
+	p.remaining -= blobGas
+	return nil
+}
+
+// checkBlobFeeCap rejects a blob tx whose fee cap cannot cover the
+// synthetic block's blob base fee, the same check a real block-builder would
+// apply before including the tx. blobBaseFee must be non-nil whenever tx
+// carries blob hashes; callers must gate on isCancun first.
+func checkBlobFeeCap(tx *types.Transaction, blobBaseFee *big.Int) error {
+	if len(tx.BlobHashes()) == 0 {
+		return nil
+	}
+	if tx.BlobGasFeeCap().Cmp(blobBaseFee) < 0 {
+		return fmt.Errorf("tx %s: blobGasFeeCap %s below block blobBaseFee %s", tx.Hash(), tx.BlobGasFeeCap(), blobBaseFee)
+	}
+	return nil
+}
+
+// accountBlobGas validates and accounts for tx's blob gas against header and
+// pool. It rejects blob txs outright when isCancun is false, rather than
+// touching header.BlobBaseFee/header.BlobGasUsed, which are nil pre-Cancun.
+func accountBlobGas(tx *types.Transaction, isCancun bool, header *evmcore.EvmHeader, pool *blobGasPool) error {
+	blobGas := tx.BlobGas()
+	if blobGas == 0 {
+		return nil
+	}
+	if !isCancun {
+		return fmt.Errorf("tx %s: blob txs require Cancun to be active", tx.Hash())
+	}
+	if err := checkBlobFeeCap(tx, header.BlobBaseFee); err != nil {
+		return err
+	}
+	if err := pool.subGas(blobGas); err != nil {
+		return fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+	}
+	*header.BlobGasUsed += blobGas
+	return nil
+}
+
+// setCodeDelegations returns the contract addresses an EIP-7702 set-code tx
+// designates as delegates, for inclusion in the per-tx jsonResult.
+func setCodeDelegations(tx *types.Transaction) []common.Address {
+	auths := tx.SetCodeAuthorizations()
+	if len(auths) == 0 {
+		return nil
+	}
+	delegations := make([]common.Address, len(auths))
+	for i, auth := range auths {
+		delegations[i] = auth.Address
+	}
+	return delegations
+}
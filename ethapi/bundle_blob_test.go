@@ -0,0 +1,79 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func newBlobTx(t *testing.T, blobFeeCap *uint256.Int) *types.Transaction {
+	t.Helper()
+	return types.NewTx(&types.BlobTx{
+		ChainID:    uint256.NewInt(1),
+		Nonce:      0,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(1),
+		Gas:        21000,
+		Value:      uint256.NewInt(0),
+		BlobFeeCap: blobFeeCap,
+		BlobHashes: []common.Hash{{0x01}},
+	})
+}
+
+func TestCheckBlobFeeCapNonBlobTx(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1)})
+	if err := checkBlobFeeCap(tx, nil); err != nil {
+		t.Fatalf("non-blob tx should never consult blobBaseFee, got err: %v", err)
+	}
+}
+
+func TestCheckBlobFeeCapBelowBaseFee(t *testing.T) {
+	tx := newBlobTx(t, uint256.NewInt(1))
+	if err := checkBlobFeeCap(tx, big.NewInt(2)); err == nil {
+		t.Fatal("expected error when blobGasFeeCap is below the block's blobBaseFee")
+	}
+}
+
+func TestBlobGasPoolExhaustion(t *testing.T) {
+	pool := newBlobGasPool()
+	if err := pool.subGas(params.MaxBlobGasPerBlock); err != nil {
+		t.Fatalf("subGas should succeed up to the pool limit: %v", err)
+	}
+	if err := pool.subGas(1); err == nil {
+		t.Fatal("expected error once the blob gas pool is exhausted")
+	}
+}
+
+// TestAccountBlobGasRejectsPreCancun is the regression test for the panic a
+// pre-Cancun eth_callBundle blob tx used to trigger: header.BlobBaseFee and
+// header.BlobGasUsed are only populated by applyBlobHeaderFields when Cancun
+// is active, so accountBlobGas must reject the tx before ever touching them.
+func TestAccountBlobGasRejectsPreCancun(t *testing.T) {
+	tx := newBlobTx(t, uint256.NewInt(1))
+	header := &evmcore.EvmHeader{} // BlobBaseFee/BlobGasUsed left nil, as they are pre-Cancun
+
+	err := accountBlobGas(tx, false /* isCancun */, header, newBlobGasPool())
+	if err == nil {
+		t.Fatal("expected an error rejecting the pre-Cancun blob tx, got nil")
+	}
+}
+
+func TestAccountBlobGasCancun(t *testing.T) {
+	tx := newBlobTx(t, uint256.NewInt(1))
+	header := &evmcore.EvmHeader{
+		BlobBaseFee: big.NewInt(1),
+		BlobGasUsed: new(uint64),
+	}
+
+	if err := accountBlobGas(tx, true, header, newBlobGasPool()); err != nil {
+		t.Fatalf("expected a Cancun blob tx with a sufficient fee cap to be accepted: %v", err)
+	}
+	if *header.BlobGasUsed != tx.BlobGas() {
+		t.Fatalf("header.BlobGasUsed = %d, want %d", *header.BlobGasUsed, tx.BlobGas())
+	}
+}
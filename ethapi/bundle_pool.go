@@ -0,0 +1,164 @@
+package ethapi
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/crypto/sha3"
+)
+
+// Bundle is a queued, signed set of transactions awaiting inclusion by the
+// block-building path, sibling to a regular pooled transaction.
+type Bundle struct {
+	Hash              common.Hash
+	Txs               types.Transactions
+	BlockNumber       rpc.BlockNumber
+	MaxBlockNumber    rpc.BlockNumber
+	MinTimestamp      *uint64
+	MaxTimestamp      *uint64
+	RevertingTxHashes []common.Hash
+	// GasPrice is the effective gas price estimated at submission time, used
+	// to order and evict bundles within the pool.
+	GasPrice *big.Int
+	// Searcher is the address recovered from the submission's
+	// X-Bundle-Signature header.
+	Searcher common.Address
+}
+
+// BundlePool holds bundles submitted via SendBundle, ordered by effective gas
+// price, until they are either included by the block-building path or expire
+// past their target block window.
+type BundlePool struct {
+	mu      sync.Mutex
+	bundles map[common.Hash]*Bundle
+
+	// maxBundles caps the size of the pool; when full, the lowest gas-price
+	// bundle already in the pool is evicted to make room for the new one,
+	// whatever the new one's gas price.
+	maxBundles int
+}
+
+// NewBundlePool creates an empty BundlePool with a default capacity.
+func NewBundlePool() *BundlePool {
+	return &BundlePool{
+		bundles:    make(map[common.Hash]*Bundle),
+		maxBundles: 1024,
+	}
+}
+
+// Add inserts a bundle into the pool, evicting the lowest gas-price bundle if
+// the pool is at capacity. Returns the bundle's hash.
+func (p *BundlePool) Add(bundle *Bundle) (common.Hash, error) {
+	if len(bundle.Txs) == 0 {
+		return common.Hash{}, errors.New("bundle missing txs")
+	}
+
+	hasher := sha3.NewLegacyKeccak256()
+	for _, tx := range bundle.Txs {
+		hasher.Write(tx.Hash().Bytes())
+	}
+	bundle.Hash = common.BytesToHash(hasher.Sum(nil))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.bundles) >= p.maxBundles {
+		p.evictLowestLocked()
+	}
+	p.bundles[bundle.Hash] = bundle
+
+	return bundle.Hash, nil
+}
+
+// evictLowestLocked removes the bundle with the lowest GasPrice. Callers must
+// hold p.mu.
+func (p *BundlePool) evictLowestLocked() {
+	var lowestHash common.Hash
+	var lowestPrice *big.Int
+	for h, b := range p.bundles {
+		if lowestPrice == nil || (b.GasPrice != nil && b.GasPrice.Cmp(lowestPrice) < 0) {
+			lowestHash = h
+			lowestPrice = b.GasPrice
+		}
+	}
+	delete(p.bundles, lowestHash)
+}
+
+// Pending returns bundles eligible for inclusion at blockNumber/timestamp,
+// ordered by descending effective gas price, after dropping bundles whose
+// target-block window or MaxTimestamp has expired and skipping bundles whose
+// MinTimestamp/MaxTimestamp is not yet satisfied. Every bundle returned is
+// immediately removed from the pool: Pending is a consume-on-read drain, not
+// a peek, so the same bundle's txs are never handed to the block-building
+// path more than once even though its [BlockNumber, MaxBlockNumber] window
+// spans several blocks.
+func (p *BundlePool) Pending(blockNumber uint64, timestamp uint64) []*Bundle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pruneExpiredLocked(blockNumber, timestamp)
+
+	pending := make([]*Bundle, 0, len(p.bundles))
+	for h, b := range p.bundles {
+		min := uint64(b.BlockNumber)
+		max := min
+		if b.MaxBlockNumber != 0 {
+			max = uint64(b.MaxBlockNumber)
+		}
+		if blockNumber < min || blockNumber > max {
+			continue
+		}
+		if b.MinTimestamp != nil && timestamp < *b.MinTimestamp {
+			continue
+		}
+		if b.MaxTimestamp != nil && timestamp > *b.MaxTimestamp {
+			continue
+		}
+		pending = append(pending, b)
+		delete(p.bundles, h)
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		pi, pj := pending[i].GasPrice, pending[j].GasPrice
+		if pi == nil || pj == nil {
+			return pi != nil
+		}
+		return pi.Cmp(pj) > 0
+	})
+
+	return pending
+}
+
+// flatten concatenates the txs of bundles, in order, into a single
+// transaction list.
+func flatten(bundles []*Bundle) types.Transactions {
+	var txs types.Transactions
+	for _, b := range bundles {
+		txs = append(txs, b.Txs...)
+	}
+	return txs
+}
+
+// pruneExpiredLocked removes bundles whose target-block window can no longer
+// be satisfied at or after blockNumber, or whose MaxTimestamp has already
+// passed. Callers must hold p.mu.
+func (p *BundlePool) pruneExpiredLocked(blockNumber uint64, timestamp uint64) {
+	for h, b := range p.bundles {
+		max := uint64(b.BlockNumber)
+		if b.MaxBlockNumber != 0 {
+			max = uint64(b.MaxBlockNumber)
+		}
+		expired := blockNumber > max
+		if b.MaxTimestamp != nil && timestamp > *b.MaxTimestamp {
+			expired = true
+		}
+		if expired {
+			delete(p.bundles, h)
+		}
+	}
+}
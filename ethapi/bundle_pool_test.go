@@ -0,0 +1,199 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func newTestBundle(t *testing.T, nonce uint64, gasPrice int64, blockNumber rpc.BlockNumber, maxBlockNumber rpc.BlockNumber) *Bundle {
+	t.Helper()
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, Gas: 21000, GasPrice: big.NewInt(1)})
+	return &Bundle{
+		Txs:            types.Transactions{tx},
+		BlockNumber:    blockNumber,
+		MaxBlockNumber: maxBlockNumber,
+		GasPrice:       big.NewInt(gasPrice),
+	}
+}
+
+func TestBundlePoolAddAssignsHash(t *testing.T) {
+	pool := NewBundlePool()
+	bundle := newTestBundle(t, 0, 1, 5, 0)
+	hash, err := pool.Add(bundle)
+	if err != nil {
+		t.Fatalf("Add returned an error: %v", err)
+	}
+	if hash == (common.Hash{}) {
+		t.Fatal("Add should assign a non-zero hash")
+	}
+}
+
+func TestBundlePoolAddRejectsEmptyBundle(t *testing.T) {
+	pool := NewBundlePool()
+	if _, err := pool.Add(&Bundle{BlockNumber: 5}); err == nil {
+		t.Fatal("expected an error adding a bundle with no txs")
+	}
+}
+
+func TestBundlePoolEvictsLowestGasPriceWhenFull(t *testing.T) {
+	pool := NewBundlePool()
+	pool.maxBundles = 2
+
+	low, _ := pool.Add(newTestBundle(t, 0, 1, 5, 0))
+	high, _ := pool.Add(newTestBundle(t, 1, 10, 5, 0))
+	evicting, _ := pool.Add(newTestBundle(t, 2, 5, 5, 0))
+
+	if len(pool.bundles) != 2 {
+		t.Fatalf("pool should stay at capacity 2, has %d", len(pool.bundles))
+	}
+	if _, ok := pool.bundles[low]; ok {
+		t.Fatal("the lowest gas-price bundle should have been evicted")
+	}
+	if _, ok := pool.bundles[high]; !ok {
+		t.Fatal("the highest gas-price bundle should have survived eviction")
+	}
+	if _, ok := pool.bundles[evicting]; !ok {
+		t.Fatal("the newly-added bundle should be present")
+	}
+}
+
+func TestBundlePoolPendingDropsExpiredBundles(t *testing.T) {
+	pool := NewBundlePool()
+	pool.Add(newTestBundle(t, 0, 1, 5, 0))  // expires after block 5
+	pool.Add(newTestBundle(t, 1, 2, 5, 10)) // valid through block 10
+
+	pending := pool.Pending(7, 0)
+	if len(pending) != 1 {
+		t.Fatalf("expected exactly 1 bundle still pending at block 7, got %d", len(pending))
+	}
+	// The expired bundle is pruned and the pending one is consumed, so the
+	// pool ends up empty.
+	if len(pool.bundles) != 0 {
+		t.Fatalf("expired bundle should have been pruned from the pool, %d remain", len(pool.bundles))
+	}
+}
+
+func TestBundlePoolPendingOrdersByDescendingGasPrice(t *testing.T) {
+	pool := NewBundlePool()
+	pool.Add(newTestBundle(t, 0, 1, 5, 20))
+	pool.Add(newTestBundle(t, 1, 10, 5, 20))
+	pool.Add(newTestBundle(t, 2, 5, 5, 20))
+
+	pending := pool.Pending(5, 0)
+	if len(pending) != 3 {
+		t.Fatalf("expected all 3 bundles pending at block 5, got %d", len(pending))
+	}
+	for i := 1; i < len(pending); i++ {
+		if pending[i-1].GasPrice.Cmp(pending[i].GasPrice) < 0 {
+			t.Fatalf("pending bundles not sorted by descending gas price: %v", pending)
+		}
+	}
+}
+
+func TestBundlePoolPendingConsumesReturnedBundles(t *testing.T) {
+	pool := NewBundlePool()
+	pool.Add(newTestBundle(t, 0, 1, 5, 10)) // spans blocks 5 through 10
+
+	pending := pool.Pending(5, 0)
+	if len(pending) != 1 {
+		t.Fatalf("expected the bundle pending at block 5, got %d", len(pending))
+	}
+	if len(pool.bundles) != 0 {
+		t.Fatalf("Pending should remove bundles it returns, %d remain", len(pool.bundles))
+	}
+
+	for block := uint64(6); block <= 10; block++ {
+		if pending := pool.Pending(block, 0); len(pending) != 0 {
+			t.Fatalf("bundle already returned at block 5 should not be pending again at block %d, got %v", block, pending)
+		}
+	}
+}
+
+func TestBundlePoolPendingRespectsMinTimestamp(t *testing.T) {
+	pool := NewBundlePool()
+	bundle := newTestBundle(t, 0, 1, 5, 0)
+	minTimestamp := uint64(100)
+	bundle.MinTimestamp = &minTimestamp
+	pool.Add(bundle)
+
+	if pending := pool.Pending(5, 50); len(pending) != 0 {
+		t.Fatalf("expected bundle to be withheld before its minTimestamp, got %v", pending)
+	}
+	if len(pool.bundles) != 1 {
+		t.Fatal("bundle should remain in the pool until minTimestamp is reached")
+	}
+	if pending := pool.Pending(5, 100); len(pending) != 1 {
+		t.Fatalf("expected bundle to be pending once minTimestamp is reached, got %v", pending)
+	}
+}
+
+func TestBundlePoolPendingDropsExpiredMaxTimestamp(t *testing.T) {
+	pool := NewBundlePool()
+	bundle := newTestBundle(t, 0, 1, 5, 0)
+	maxTimestamp := uint64(100)
+	bundle.MaxTimestamp = &maxTimestamp
+	pool.Add(bundle)
+
+	if pending := pool.Pending(5, 150); len(pending) != 0 {
+		t.Fatalf("expected bundle past its maxTimestamp to be withheld, got %v", pending)
+	}
+	if len(pool.bundles) != 0 {
+		t.Fatal("bundle past its maxTimestamp should have been pruned from the pool")
+	}
+}
+
+func TestPrependPendingBundlesMergesAndRecordsLanded(t *testing.T) {
+	api := &BundleAPI{pool: NewBundlePool(), reputation: NewReputationTracker()}
+	searcher := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bundle := newTestBundle(t, 0, 5, 5, 0)
+	bundle.Searcher = searcher
+	api.pool.Add(bundle)
+
+	blockTx := types.NewTx(&types.LegacyTx{Nonce: 9, Gas: 21000, GasPrice: big.NewInt(1)})
+	merged := api.PrependPendingBundles(5, 0, types.Transactions{blockTx})
+
+	if len(merged) != 2 || merged[0].Hash() != bundle.Txs[0].Hash() {
+		t.Fatalf("expected the pending bundle's tx to be prepended, got %v", merged)
+	}
+	if stats := api.SearcherStats(searcher); stats.LandedBundles != 1 {
+		t.Fatalf("expected PrependPendingBundles to record a landed bundle, got %+v", stats)
+	}
+}
+
+func TestAssembleBlockTxsMergesEncodedTxs(t *testing.T) {
+	api := &BundleAPI{pool: NewBundlePool(), reputation: NewReputationTracker()}
+	searcher := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	bundle := newTestBundle(t, 0, 5, 5, 0)
+	bundle.Searcher = searcher
+	api.pool.Add(bundle)
+
+	blockTx := types.NewTx(&types.LegacyTx{Nonce: 9, Gas: 21000, GasPrice: big.NewInt(1)})
+	encodedBlockTx, err := blockTx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode block tx: %v", err)
+	}
+
+	merged, err := api.AssembleBlockTxs(5, 0, []hexutil.Bytes{encodedBlockTx})
+	if err != nil {
+		t.Fatalf("AssembleBlockTxs returned an error: %v", err)
+	}
+	if len(merged) != 2 {
+		t.Fatalf("expected the pending bundle's tx to be prepended, got %d txs", len(merged))
+	}
+
+	var prependedTx types.Transaction
+	if err := prependedTx.UnmarshalBinary(merged[0]); err != nil {
+		t.Fatalf("failed to decode prepended tx: %v", err)
+	}
+	if prependedTx.Hash() != bundle.Txs[0].Hash() {
+		t.Fatalf("expected the bundle's tx to be prepended first, got %v", prependedTx.Hash())
+	}
+	if stats := api.SearcherStats(searcher); stats.LandedBundles != 1 {
+		t.Fatalf("expected AssembleBlockTxs to record a landed bundle, got %+v", stats)
+	}
+}
@@ -0,0 +1,209 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// bundleSignatureHeader is the header a searcher signs the JSON-RPC request
+// body with: "<address>:<unix-timestamp>:<signature>", where signature
+// covers both body and timestamp, so replaying with a modified timestamp
+// invalidates the signature.
+const bundleSignatureHeader = "X-Bundle-Signature"
+
+// bundleSignatureFreshness is the maximum allowed skew between a signature's
+// timestamp and the server's current time; signatures outside this window
+// are rejected, bounding how long a captured request can be replayed.
+const bundleSignatureFreshness = 30 * time.Second
+
+type searcherContextKey struct{}
+
+// SearcherFromContext returns the searcher address verified by
+// BundleSignatureMiddleware for this request, if any.
+func SearcherFromContext(ctx context.Context) (common.Address, bool) {
+	addr, ok := ctx.Value(searcherContextKey{}).(common.Address)
+	return addr, ok
+}
+
+// withoutSearcher returns a context derived from ctx with the searcher
+// identity removed, while delegating deadlines/cancellation/other values to
+// ctx as usual. Used for internally-initiated simulation calls that should
+// not be attributed to the submitting searcher, so downstream code doesn't
+// double-count a failure against the same searcher.
+func withoutSearcher(ctx context.Context) context.Context {
+	return noSearcherContext{ctx}
+}
+
+type noSearcherContext struct {
+	context.Context
+}
+
+func (c noSearcherContext) Value(key interface{}) interface{} {
+	if _, ok := key.(searcherContextKey); ok {
+		return nil
+	}
+	return c.Context.Value(key)
+}
+
+// verifyBundleSignature recovers the signing address from header over body
+// and its carried timestamp, checks it matches the address the header
+// claims to be from, and rejects a timestamp that is too old or too far
+// ahead of now (outside bundleSignatureFreshness), so a captured request
+// cannot be replayed outside that window.
+func verifyBundleSignature(body []byte, header string, now time.Time) (common.Address, error) {
+	parts := strings.SplitN(header, ":", 3)
+	if len(parts) != 3 {
+		return common.Address{}, errors.New("malformed X-Bundle-Signature header, want \"<address>:<timestamp>:<signature>\"")
+	}
+	claimed := common.HexToAddress(parts[0])
+
+	timestamp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > bundleSignatureFreshness || skew < -bundleSignatureFreshness {
+		return common.Address{}, fmt.Errorf("timestamp %d outside the %s freshness window", timestamp, bundleSignatureFreshness)
+	}
+
+	sig, err := hexutil.Decode(parts[2])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	// crypto.Ecrecover expects a recovery id in [0, 3]; personal_sign style
+	// signatures encode it as 27/28 in the last byte.
+	if sig[crypto.RecoveryIDOffset] == 27 || sig[crypto.RecoveryIDOffset] == 28 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	signedPayload := append(append([]byte{}, body...), []byte(":"+parts[1])...)
+	hash := accounts.TextHash(crypto.Keccak256(signedPayload))
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover searcher address: %w", err)
+	}
+
+	recovered := crypto.PubkeyToAddress(*pubkey)
+	if recovered != claimed {
+		return common.Address{}, fmt.Errorf("signature does not match claimed address %s", claimed)
+	}
+	return recovered, nil
+}
+
+// bundleReplayGuard rejects a signature already seen within twice
+// bundleSignatureFreshness, the full width of the window in which
+// verifyBundleSignature accepts a timestamp, so a byte-for-byte replay of a
+// captured, validly-signed request is refused for as long as its timestamp
+// is still fresh.
+type bundleReplayGuard struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newBundleReplayGuard() *bundleReplayGuard {
+	return &bundleReplayGuard{seen: make(map[string]time.Time)}
+}
+
+// observe reports whether sig has not been seen within twice
+// bundleSignatureFreshness of now, recording it if so, and prunes entries
+// older than that.
+func (g *bundleReplayGuard) observe(sig string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for s, seenAt := range g.seen {
+		if now.Sub(seenAt) > 2*bundleSignatureFreshness {
+			delete(g.seen, s)
+		}
+	}
+
+	if seenAt, ok := g.seen[sig]; ok && now.Sub(seenAt) <= 2*bundleSignatureFreshness {
+		return false
+	}
+	g.seen[sig] = now
+	return true
+}
+
+// BundleSignatureMiddleware wraps an RPC HTTP handler so that a request
+// carrying an X-Bundle-Signature header reaches the wrapped handler only if
+// that header is valid, fresh, and not yet replayed. On success, the verified searcher address is
+// attached to the request context for downstream handlers (e.g. SendBundle,
+// CallBundle) to consult. Requests without the header are passed through
+// unmodified, since not every RPC method on the server requires a
+// registered searcher. Each call creates its own bundleReplayGuard, so
+// multiple requests against the same handler share replay-detection state.
+func BundleSignatureMiddleware(next http.Handler) http.Handler {
+	guard := newBundleReplayGuard()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig := r.Header.Get(bundleSignatureHeader)
+		if sig == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		searcher, err := verifyBundleSignature(body, sig, time.Now())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid bundle signature: %v", err), http.StatusUnauthorized)
+			return
+		}
+		if !guard.observe(sig, time.Now()) {
+			http.Error(w, "bundle signature already used, possible replay", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), searcherContextKey{}, searcher)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewBundleRPCHandler wraps rpcHandler, the node's JSON-RPC HTTP handler,
+// with BundleSignatureMiddleware, so CallBundle/SendBundle can recover a
+// verified searcher identity via SearcherFromContext.
+//
+// A full node wires this in by wrapping its own existing JSON-RPC HTTP
+// handler with it during server setup, which this package cannot do on its
+// own since it has no node bootstrap code to hook into. NewBundleRPCServer
+// below is the minimal real call site this package does provide, standing
+// up a self-contained JSON-RPC server behind this handler so the signature
+// requirement is exercised against a real HTTP request rather than only
+// against hand-built ones in tests.
+func NewBundleRPCHandler(rpcHandler http.Handler) http.Handler {
+	return BundleSignatureMiddleware(rpcHandler)
+}
+
+// NewBundleRPCServer registers api under the "eth" namespace on a new
+// *rpc.Server and wraps it with NewBundleRPCHandler, so a request against the
+// returned handler that carries an X-Bundle-Signature header reaches api only
+// if that header is valid, exactly as it would on a node that wrapped its own
+// RPC server the same way. Requests without the header still reach api:
+// eth_sendBundle then rejects them, while eth_callBundle simulates them
+// without a searcher identity.
+func NewBundleRPCServer(api *BundleAPI) (*rpc.Server, http.Handler, error) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		return nil, nil, err
+	}
+	return rpcServer, NewBundleRPCHandler(rpcServer), nil
+}
@@ -0,0 +1,272 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func signBundleBody(t *testing.T, key []byte, body []byte, timestamp time.Time) string {
+	t.Helper()
+	privKey, err := crypto.ToECDSA(key)
+	if err != nil {
+		t.Fatalf("failed to load private key: %v", err)
+	}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	hash := accounts.TextHash(crypto.Keccak256(append(append([]byte{}, body...), []byte(":"+ts)...)))
+	sig, err := crypto.Sign(hash, privKey)
+	if err != nil {
+		t.Fatalf("failed to sign body: %v", err)
+	}
+	addr := crypto.PubkeyToAddress(privKey.PublicKey)
+	return addr.Hex() + ":" + ts + ":" + hexutil.Encode(sig)
+}
+
+func testPrivateKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return crypto.FromECDSA(key)
+}
+
+func TestVerifyBundleSignatureRoundTrip(t *testing.T) {
+	body := []byte(`{"method":"eth_sendBundle"}`)
+	key := testPrivateKey(t)
+	now := time.Now()
+	header := signBundleBody(t, key, body, now)
+
+	addr, err := verifyBundleSignature(body, header, now)
+	if err != nil {
+		t.Fatalf("expected a valid signature to verify, got error: %v", err)
+	}
+	if !strings.HasPrefix(header, addr.Hex()) {
+		t.Fatalf("recovered address %s does not match the claimed address in %q", addr, header)
+	}
+}
+
+func TestVerifyBundleSignatureRejectsTamperedBody(t *testing.T) {
+	key := testPrivateKey(t)
+	now := time.Now()
+	header := signBundleBody(t, key, []byte(`{"method":"eth_sendBundle"}`), now)
+
+	if _, err := verifyBundleSignature([]byte(`{"method":"eth_callBundle"}`), header, now); err == nil {
+		t.Fatal("expected verification to fail once the signed body is tampered with")
+	}
+}
+
+func TestVerifyBundleSignatureRejectsMalformedHeader(t *testing.T) {
+	if _, err := verifyBundleSignature([]byte("body"), "not-a-valid-header", time.Now()); err == nil {
+		t.Fatal("expected an error for a header missing the address:timestamp:signature separator")
+	}
+}
+
+func TestVerifyBundleSignatureRejectsStaleTimestamp(t *testing.T) {
+	body := []byte(`{"method":"eth_sendBundle"}`)
+	key := testPrivateKey(t)
+	signedAt := time.Now().Add(-time.Hour)
+	header := signBundleBody(t, key, body, signedAt)
+
+	if _, err := verifyBundleSignature(body, header, time.Now()); err == nil {
+		t.Fatal("expected an error for a timestamp outside the freshness window")
+	}
+}
+
+func TestBundleSignatureMiddlewareAttachesSearcher(t *testing.T) {
+	body := []byte(`{"method":"eth_sendBundle"}`)
+	key := testPrivateKey(t)
+	header := signBundleBody(t, key, body, time.Now())
+
+	var gotSearcher bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, gotSearcher = SearcherFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := NewBundleRPCHandler(next)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	req.Header.Set(bundleSignatureHeader, header)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for a validly signed request, got %d", rr.Code)
+	}
+	if !gotSearcher {
+		t.Fatal("expected the handler to observe a verified searcher in the request context")
+	}
+}
+
+func TestBundleSignatureMiddlewareRejectsBadSignature(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("the wrapped handler should not run on an invalid signature")
+	})
+
+	handler := NewBundleRPCHandler(next)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(bundleSignatureHeader, "0x0000000000000000000000000000000000000000:"+ts+":0xdeadbeef")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 for an invalid signature, got %d", rr.Code)
+	}
+}
+
+func TestBundleSignatureMiddlewareRejectsReplayedSignature(t *testing.T) {
+	body := []byte(`{"method":"eth_sendBundle"}`)
+	key := testPrivateKey(t)
+	header := signBundleBody(t, key, body, time.Now())
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewBundleRPCHandler(next)
+
+	first := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	first.Header.Set(bundleSignatureHeader, header)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, first)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the first use of the signature to succeed, got %d", rr.Code)
+	}
+
+	replay := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	replay.Header.Set(bundleSignatureHeader, header)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, replay)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a byte-for-byte replay of the same signed request to be rejected, got %d", rr.Code)
+	}
+}
+
+func TestWithoutSearcherStripsSearcherButKeepsOtherValues(t *testing.T) {
+	type otherKey struct{}
+	searcher := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	ctx := context.WithValue(context.Background(), searcherContextKey{}, searcher)
+	ctx = context.WithValue(ctx, otherKey{}, "keep-me")
+
+	stripped := withoutSearcher(ctx)
+	if _, ok := SearcherFromContext(stripped); ok {
+		t.Fatal("withoutSearcher should strip the searcher from the derived context")
+	}
+	if got := stripped.Value(otherKey{}); got != "keep-me" {
+		t.Fatalf("withoutSearcher should delegate unrelated keys, got %v", got)
+	}
+}
+
+func TestBundleSignatureMiddlewarePassesThroughWithoutHeader(t *testing.T) {
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := NewBundleRPCHandler(next)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if !called || rr.Code != http.StatusOK {
+		t.Fatal("requests without X-Bundle-Signature should pass through unmodified")
+	}
+}
+
+// sendBundleRPCRequest posts a JSON-RPC eth_sendBundle call against handler
+// and returns the JSON-RPC error message, if any, so tests can assert on how
+// far the call got without needing a real Backend.
+func sendBundleRPCRequest(t *testing.T, handler http.Handler, sign bool) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_sendBundle",
+		"params":  []interface{}{map[string]interface{}{"txs": []string{}, "blockNumber": "0x1"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if sign {
+		req.Header.Set(bundleSignatureHeader, signBundleBody(t, testPrivateKey(t), body, time.Now()))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	var rpcResp struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
+		t.Fatalf("failed to unmarshal JSON-RPC response %q: %v", respBody, err)
+	}
+	if rpcResp.Error == nil {
+		t.Fatalf("expected eth_sendBundle to fail (no Backend is wired in this test), got response %q", respBody)
+	}
+	return rpcResp.Error.Message
+}
+
+// TestBundleRPCServerRejectsUnsignedSendBundleEndToEnd proves
+// NewBundleRPCServer's signature requirement end-to-end over a real HTTP +
+// JSON-RPC round trip, rather than only against a hand-built middleware
+// call: an eth_sendBundle request with no X-Bundle-Signature header never
+// reaches SendBundle's own validation.
+func TestBundleRPCServerRejectsUnsignedSendBundleEndToEnd(t *testing.T) {
+	api := &BundleAPI{pool: NewBundlePool(), reputation: NewReputationTracker()}
+	_, handler, err := NewBundleRPCServer(api)
+	if err != nil {
+		t.Fatalf("failed to build bundle RPC server: %v", err)
+	}
+
+	if msg := sendBundleRPCRequest(t, handler, false); msg != "bundle missing a valid X-Bundle-Signature header" {
+		t.Fatalf("expected an unsigned request to be rejected for a missing signature, got %q", msg)
+	}
+}
+
+// TestBundleRPCServerAttachesSearcherEndToEnd proves a validly-signed
+// request reaches SendBundle with a verified searcher attached: it gets
+// past the signature check and fails instead on SendBundle's own "missing
+// txs" validation, rather than on the signature requirement.
+func TestBundleRPCServerAttachesSearcherEndToEnd(t *testing.T) {
+	api := &BundleAPI{pool: NewBundlePool(), reputation: NewReputationTracker()}
+	_, handler, err := NewBundleRPCServer(api)
+	if err != nil {
+		t.Fatalf("failed to build bundle RPC server: %v", err)
+	}
+
+	if msg := sendBundleRPCRequest(t, handler, true); msg != "bundle missing txs" {
+		t.Fatalf("expected a signed request to reach SendBundle's own validation, got %q", msg)
+	}
+}
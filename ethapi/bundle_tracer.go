@@ -0,0 +1,50 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+
+	// registers the callTracer/prestateTracer/4byteTracer native tracers
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
+)
+
+// NewTracerConfig builds the vm.Config to use for a single transaction,
+// wiring in the named tracer ("callTracer", "prestateTracer", "4byteTracer",
+// or a raw JS tracer expression) when one is requested. A nil name returns a
+// zero-value, untraced vm.Config.
+func NewTracerConfig(name *string, txHash, blockHash common.Hash, blockNumber uint64) (*vm.Config, *tracers.Tracer, error) {
+	if name == nil || *name == "" {
+		return &vm.Config{}, nil, nil
+	}
+
+	tracer, err := tracers.DefaultDirectory.New(*name, &tracers.Context{
+		BlockHash: blockHash,
+		TxHash:    txHash,
+	}, json.RawMessage("{}"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to instantiate tracer %q: %w", *name, err)
+	}
+
+	return &vm.Config{Tracer: tracer.Hooks}, tracer, nil
+}
+
+// TraceResult extracts the structured trace produced by tracer, suitable for
+// embedding in a per-tx jsonResult under the "trace" key.
+func TraceResult(tracer *tracers.Tracer) (interface{}, error) {
+	if tracer == nil {
+		return nil, nil
+	}
+	raw, err := tracer.GetResult()
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package ethapi
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewTracerConfigNilName(t *testing.T) {
+	vmConfig, tracer, err := NewTracerConfig(nil, common.Hash{}, common.Hash{}, 1)
+	if err != nil {
+		t.Fatalf("a nil tracer name should not error, got: %v", err)
+	}
+	if tracer != nil {
+		t.Fatal("a nil tracer name should not instantiate a tracer")
+	}
+	if vmConfig.Tracer != nil {
+		t.Fatal("a nil tracer name should return an untraced vm.Config")
+	}
+}
+
+func TestNewTracerConfigUnknownTracer(t *testing.T) {
+	name := "notARealTracer"
+	if _, _, err := NewTracerConfig(&name, common.Hash{}, common.Hash{}, 1); err == nil {
+		t.Fatal("expected an error for an unknown tracer name")
+	}
+}
+
+func TestNewTracerConfigKnownTracer(t *testing.T) {
+	name := "callTracer"
+	vmConfig, tracer, err := NewTracerConfig(&name, common.Hash{0x01}, common.Hash{0x02}, 1)
+	if err != nil {
+		t.Fatalf("callTracer should be registered via the native tracers import, got err: %v", err)
+	}
+	if tracer == nil {
+		t.Fatal("expected a non-nil tracer for a known tracer name")
+	}
+	if vmConfig.Tracer == nil {
+		t.Fatal("expected the vm.Config to carry the tracer's hooks")
+	}
+}
+
+func TestTraceResultNilTracer(t *testing.T) {
+	result, err := TraceResult(nil)
+	if err != nil {
+		t.Fatalf("a nil tracer should not error, got: %v", err)
+	}
+	if result != nil {
+		t.Fatal("a nil tracer should produce a nil trace result")
+	}
+}
+
+func TestTraceResultUnmarshalsTracerOutput(t *testing.T) {
+	// 4byteTracer yields a valid (empty) result before any execution, unlike
+	// callTracer, which reports an error until a top-level call is recorded.
+	name := "4byteTracer"
+	_, tracer, err := NewTracerConfig(&name, common.Hash{}, common.Hash{}, 1)
+	if err != nil {
+		t.Fatalf("failed to instantiate 4byteTracer: %v", err)
+	}
+
+	result, err := TraceResult(tracer)
+	if err != nil {
+		t.Fatalf("expected 4byteTracer's default result to unmarshal cleanly, got err: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected a non-nil trace result for an instantiated tracer")
+	}
+}
@@ -0,0 +1,101 @@
+package ethapi
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+)
+
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of a CallBundle. Nil values are ignored, leaving the existing
+// state untouched. Only one of State or StateDiff may be set: State fully
+// replaces the storage of the account, StateDiff patches individual slots.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64             `json:"nonce"`
+	Code      *hexutil.Bytes              `json:"code"`
+	Balance   **hexutil.Big               `json:"balance"`
+	State     map[common.Hash]common.Hash `json:"state"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is a set of per-account overrides, keyed by address, applied
+// to the StateDB snapshot before a bundle is simulated.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the given StateDB with the values held in
+// the StateOverride, returning an error if both State and StateDiff are set
+// for the same account.
+func (overrides StateOverride) Apply(statedb state.StateDB) error {
+	for addr, account := range overrides {
+		if account.Nonce != nil {
+			statedb.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			statedb.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			balance, overflow := uint256.FromBig((*account.Balance).ToInt())
+			if overflow {
+				return fmt.Errorf("account %s: balance override overflows uint256", addr)
+			}
+			statedb.SetBalance(addr, balance)
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s: state and stateDiff overrides are mutually exclusive", addr)
+		}
+		if account.State != nil {
+			statedb.SetStorage(addr, account.State)
+		}
+		for key, value := range account.StateDiff {
+			statedb.SetState(addr, key, value)
+		}
+	}
+	return nil
+}
+
+// BlockOverrides replaces the fields of an evmcore.EvmHeader before a bundle
+// is simulated, allowing what-if execution against a hypothetical block
+// (e.g. a different timestamp, base fee or coinbase) without first mining it.
+type BlockOverrides struct {
+	Number      *hexutil.Big    `json:"number"`
+	Time        *hexutil.Uint64 `json:"time"`
+	GasLimit    *hexutil.Uint64 `json:"gasLimit"`
+	Coinbase    *common.Address `json:"coinbase"`
+	BaseFee     *hexutil.Big    `json:"baseFee"`
+	Random      *common.Hash    `json:"random"`
+	BlobBaseFee *hexutil.Big    `json:"blobBaseFee"`
+}
+
+// Apply overrides the corresponding fields of header with any non-nil value
+// held in the BlockOverrides.
+func (overrides *BlockOverrides) Apply(header *evmcore.EvmHeader) {
+	if overrides == nil {
+		return
+	}
+	if overrides.Number != nil {
+		header.Number = overrides.Number.ToInt()
+	}
+	if overrides.Time != nil {
+		header.Time = inter.Timestamp(*overrides.Time)
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.Coinbase != nil {
+		header.Coinbase = *overrides.Coinbase
+	}
+	if overrides.BaseFee != nil {
+		header.BaseFee = overrides.BaseFee.ToInt()
+	}
+	if overrides.Random != nil {
+		header.PrevRandao = *overrides.Random
+	}
+	if overrides.BlobBaseFee != nil {
+		header.BlobBaseFee = overrides.BlobBaseFee.ToInt()
+	}
+}
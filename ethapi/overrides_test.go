@@ -0,0 +1,85 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestStateOverrideApplyNilIsNoop(t *testing.T) {
+	overrides := StateOverride{}
+	if err := overrides.Apply(nil); err != nil {
+		t.Fatalf("an empty StateOverride should never touch the statedb, got err: %v", err)
+	}
+}
+
+func TestStateOverrideApplyRejectsStateAndStateDiff(t *testing.T) {
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	overrides := StateOverride{
+		addr: OverrideAccount{
+			State:     map[common.Hash]common.Hash{{0x01}: {0x02}},
+			StateDiff: map[common.Hash]common.Hash{{0x03}: {0x04}},
+		},
+	}
+	if err := overrides.Apply(nil); err == nil {
+		t.Fatal("expected an error when both State and StateDiff are set for the same account")
+	}
+}
+
+func TestBlockOverridesApplyNilReceiver(t *testing.T) {
+	var overrides *BlockOverrides
+	header := &evmcore.EvmHeader{Number: big.NewInt(1)}
+
+	overrides.Apply(header)
+
+	if header.Number.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("a nil *BlockOverrides should leave header untouched, got Number = %s", header.Number)
+	}
+}
+
+func TestBlockOverridesApplyOverridesFields(t *testing.T) {
+	coinbase := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	random := common.Hash{0x05}
+	overrides := &BlockOverrides{
+		Number:      (*hexutil.Big)(big.NewInt(42)),
+		Time:        newUint64(100),
+		GasLimit:    newUint64(21000),
+		Coinbase:    &coinbase,
+		BaseFee:     (*hexutil.Big)(big.NewInt(7)),
+		Random:      &random,
+		BlobBaseFee: (*hexutil.Big)(big.NewInt(3)),
+	}
+	header := &evmcore.EvmHeader{Number: big.NewInt(1)}
+
+	overrides.Apply(header)
+
+	if header.Number.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("Number = %s, want 42", header.Number)
+	}
+	if uint64(header.Time) != 100 {
+		t.Fatalf("Time = %d, want 100", header.Time)
+	}
+	if header.GasLimit != 21000 {
+		t.Fatalf("GasLimit = %d, want 21000", header.GasLimit)
+	}
+	if header.Coinbase != coinbase {
+		t.Fatalf("Coinbase = %s, want %s", header.Coinbase, coinbase)
+	}
+	if header.BaseFee.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("BaseFee = %s, want 7", header.BaseFee)
+	}
+	if header.PrevRandao != random {
+		t.Fatalf("PrevRandao = %s, want %s", header.PrevRandao, random)
+	}
+	if header.BlobBaseFee.Cmp(big.NewInt(3)) != 0 {
+		t.Fatalf("BlobBaseFee = %s, want 3", header.BlobBaseFee)
+	}
+}
+
+func newUint64(v uint64) *hexutil.Uint64 {
+	u := hexutil.Uint64(v)
+	return &u
+}
@@ -0,0 +1,96 @@
+package ethapi
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SearcherReputation tracks a single searcher's bundle-landing history,
+// keyed by the address recovered from its X-Bundle-Signature header.
+type SearcherReputation struct {
+	LandedBundles uint64   `json:"landedBundles"`
+	FailedBundles uint64   `json:"failedBundles"`
+	EffGasPrice   *big.Int `json:"effectiveGasPrice"`
+}
+
+// ReputationTracker is an in-memory registry of SearcherReputation, sibling
+// to the BundlePool, consulted by SendBundle/CallBundle for rate-limit
+// decisions. It does not affect the order in which pooled bundles are
+// included; BundlePool orders by gas price alone.
+type ReputationTracker struct {
+	mu    sync.Mutex
+	stats map[common.Address]*SearcherReputation
+}
+
+// NewReputationTracker creates an empty ReputationTracker.
+func NewReputationTracker() *ReputationTracker {
+	return &ReputationTracker{
+		stats: make(map[common.Address]*SearcherReputation),
+	}
+}
+
+// RecordLanded records that a bundle submitted by searcher was included,
+// updating its effective gas price to gasPrice (for pooled bundles, the
+// price estimated when the bundle was submitted).
+func (r *ReputationTracker) RecordLanded(searcher common.Address, gasPrice *big.Int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.entryLocked(searcher)
+	s.LandedBundles++
+	s.EffGasPrice = gasPrice
+}
+
+// RecordFailed records that a bundle submitted by searcher failed to land.
+func (r *ReputationTracker) RecordFailed(searcher common.Address) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entryLocked(searcher).FailedBundles++
+}
+
+// Stats returns a copy of the tracked reputation for searcher, or a
+// zero-value SearcherReputation if nothing has been recorded yet.
+func (r *ReputationTracker) Stats(searcher common.Address) SearcherReputation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.stats[searcher]; ok {
+		return *s
+	}
+	return SearcherReputation{}
+}
+
+// maxFailedBundles is the number of failed simulations/submissions a
+// searcher may accumulate before CallBundle and SendBundle reject its signed
+// requests. The failure count is never reset, so the rejection lasts for the
+// lifetime of the tracker.
+const maxFailedBundles = 50
+
+// Allow reports whether searcher's signed CallBundle/SendBundle requests are
+// accepted, based on its recorded failure history. An unknown searcher (no
+// X-Bundle-Signature header, or never seen before) is always allowed, since
+// CallBundle is also used for unauthenticated simulation.
+func (r *ReputationTracker) Allow(searcher common.Address) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[searcher]
+	if !ok {
+		return true
+	}
+	return s.FailedBundles < maxFailedBundles
+}
+
+// entryLocked returns the SearcherReputation for searcher, creating one if
+// absent. Callers must hold r.mu.
+func (r *ReputationTracker) entryLocked(searcher common.Address) *SearcherReputation {
+	s, ok := r.stats[searcher]
+	if !ok {
+		s = &SearcherReputation{}
+		r.stats[searcher] = s
+	}
+	return s
+}
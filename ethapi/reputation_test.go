@@ -0,0 +1,67 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestReputationTrackerAllowUnknownSearcher(t *testing.T) {
+	tracker := NewReputationTracker()
+	searcher := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	if !tracker.Allow(searcher) {
+		t.Fatal("a searcher with no recorded history should always be allowed")
+	}
+}
+
+func TestReputationTrackerAllowUpToThreshold(t *testing.T) {
+	tracker := NewReputationTracker()
+	searcher := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	for i := 0; i < maxFailedBundles-1; i++ {
+		tracker.RecordFailed(searcher)
+	}
+	if !tracker.Allow(searcher) {
+		t.Fatal("a searcher just below maxFailedBundles should still be allowed")
+	}
+}
+
+func TestReputationTrackerDisallowsAfterThreshold(t *testing.T) {
+	tracker := NewReputationTracker()
+	searcher := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	for i := 0; i < maxFailedBundles; i++ {
+		tracker.RecordFailed(searcher)
+	}
+	if tracker.Allow(searcher) {
+		t.Fatal("a searcher at maxFailedBundles should be throttled")
+	}
+}
+
+func TestReputationTrackerRecordLandedUpdatesStats(t *testing.T) {
+	tracker := NewReputationTracker()
+	searcher := common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+	tracker.RecordLanded(searcher, big.NewInt(5))
+	tracker.RecordFailed(searcher)
+
+	stats := tracker.Stats(searcher)
+	if stats.LandedBundles != 1 {
+		t.Fatalf("LandedBundles = %d, want 1", stats.LandedBundles)
+	}
+	if stats.FailedBundles != 1 {
+		t.Fatalf("FailedBundles = %d, want 1", stats.FailedBundles)
+	}
+	if stats.EffGasPrice.Cmp(big.NewInt(5)) != 0 {
+		t.Fatalf("EffGasPrice = %s, want 5", stats.EffGasPrice)
+	}
+}
+
+func TestReputationTrackerStatsUnknownSearcher(t *testing.T) {
+	tracker := NewReputationTracker()
+	searcher := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	stats := tracker.Stats(searcher)
+	if stats != (SearcherReputation{}) {
+		t.Fatalf("expected a zero-value SearcherReputation for an unknown searcher, got %+v", stats)
+	}
+}
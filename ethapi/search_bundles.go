@@ -0,0 +1,285 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// bundleScore selects the metric SearchBundles ranks candidate orderings by.
+type bundleScore string
+
+const (
+	scoreCoinbaseDiff      bundleScore = "coinbaseDiff"
+	scoreGasPrice          bundleScore = "gasPrice"
+	scoreEthSentToCoinbase bundleScore = "ethSentToCoinbase"
+)
+
+// maxSearchWorkers bounds how many orderings are scored concurrently,
+// regardless of how many orderings the caller asked SearchBundles to
+// explore, so a single mev_searchBundles call cannot fan out more than this
+// many concurrent StateDB/header fetches against the backend.
+const maxSearchWorkers = 8
+
+// SearchBundlesArgs is the input to SearchBundles: a candidate set of txs to
+// explore orderings of, and the scoring function used to rank them.
+type SearchBundlesArgs struct {
+	Txs                    []hexutil.Bytes       `json:"txs"`
+	BlockNumber            rpc.BlockNumber       `json:"blockNumber"`
+	StateBlockNumberOrHash rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Score                  bundleScore           `json:"score"`
+	MaxPermutations        int                   `json:"maxPermutations"`
+	Timeout                *int64                `json:"timeout"`
+}
+
+// RankedOrdering is one scored ordering returned by SearchBundles.
+type RankedOrdering struct {
+	Txs   []hexutil.Bytes `json:"txs"`
+	Score string          `json:"score"`
+}
+
+// SearchBundles explores alternative orderings of the given candidate txs,
+// simulating each in parallel against its own StateDB — cheaply snapshotted
+// from one shared base where the backend's StateDB supports it, or otherwise
+// fetched fresh per worker — and returns the orderings ranked by the
+// requested score, highest first. Useful for searchers probing sandwich/arb
+// opportunities across several candidate orderings without committing to
+// one up front.
+func (s *BundleAPI) SearchBundles(ctx context.Context, args SearchBundlesArgs) ([]RankedOrdering, error) {
+	if len(args.Txs) == 0 {
+		return nil, errors.New("search missing txs")
+	}
+	if args.BlockNumber == 0 {
+		return nil, errors.New("search missing blockNumber")
+	}
+	switch args.Score {
+	case scoreCoinbaseDiff, scoreGasPrice, scoreEthSentToCoinbase:
+	case "":
+		args.Score = scoreCoinbaseDiff
+	default:
+		return nil, fmt.Errorf("unknown score function %q", args.Score)
+	}
+
+	maxPermutations := args.MaxPermutations
+	if maxPermutations <= 0 || maxPermutations > 120 {
+		maxPermutations = 120
+	}
+
+	timeoutMilliSeconds := int64(5000)
+	if args.Timeout != nil {
+		timeoutMilliSeconds = *args.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*time.Duration(timeoutMilliSeconds))
+	defer cancel()
+
+	// Fetched once to validate the requested block/state window and, where
+	// possible, serve as the base every worker snapshots from instead of
+	// each doing its own backend round trip; see stateCopier below.
+	baseState, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, args.StateBlockNumberOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	orderings := permutations(args.Txs, maxPermutations)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		ranked []RankedOrdering
+	)
+	sem := make(chan struct{}, maxSearchWorkers)
+	for _, ordering := range orderings {
+		ordering := ordering
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			score, err := s.scoreOrdering(ctx, args.BlockNumber, args.StateBlockNumberOrHash, baseState, parent, ordering, args.Score)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			ranked = append(ranked, RankedOrdering{Txs: ordering, Score: score.String()})
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(ranked, func(i, j int) bool {
+		si, _ := new(big.Int).SetString(ranked[i].Score, 10)
+		sj, _ := new(big.Int).SetString(ranked[j].Score, 10)
+		if si == nil || sj == nil {
+			return false
+		}
+		return si.Cmp(sj) > 0
+	})
+
+	return ranked, nil
+}
+
+// stateCopier is implemented by a StateDB that can cheaply snapshot itself
+// for simulation, as an alternative to a full backend round trip.
+//
+// TODO(chunk0-5): inter/state.StateDB does not implement this yet, so
+// scoreOrdering always falls back to fetching its own StateDB from the
+// backend per worker per ordering. Once inter/state grows a
+// CopyForSimulation (or equivalent) method satisfying this interface,
+// scoreOrdering picks it up automatically with no further change here; flag
+// adding it to whoever owns inter/state before maxSearchWorkers or
+// maxPermutations are raised.
+type stateCopier interface {
+	CopyForSimulation() state.StateDB
+}
+
+// scoreOrdering executes one candidate ordering against a StateDB and
+// returns the requested score. parent is the EvmHeader SearchBundles fetched
+// once up front; it is read-only here, so every worker shares it safely. The
+// StateDB is the only thing a worker must not share: if baseState implements
+// stateCopier, each worker snapshots it cheaply instead of fetching its own
+// StateDB from the backend; otherwise it falls back to a backend round trip,
+// so concurrent workers never contend on (or race over) the same StateDB.
+func (s *BundleAPI) scoreOrdering(ctx context.Context, blockNumber rpc.BlockNumber, stateBlockNumberOrHash rpc.BlockNumberOrHash, baseState state.StateDB, parent *evmcore.EvmHeader, encodedTxs []hexutil.Bytes, score bundleScore) (*big.Int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var simState state.StateDB
+	if copier, ok := baseState.(stateCopier); ok {
+		simState = copier.CopyForSimulation()
+	} else {
+		var err error
+		simState, _, err = s.b.StateAndHeaderByNumberOrHash(ctx, stateBlockNumberOrHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if simState == nil {
+		return nil, errors.New("nil StateDB")
+	}
+
+	var baseFee *big.Int
+	if s.b.ChainConfig().IsLondon(big.NewInt(blockNumber.Int64())) {
+		baseFee = eip1559.CalcBaseFee(s.b.ChainConfig(), parent.EthHeader())
+	}
+	header := &evmcore.EvmHeader{
+		ParentHash: parent.Hash,
+		Number:     big.NewInt(int64(blockNumber)),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Coinbase:   parent.Coinbase,
+		BaseFee:    baseFee,
+	}
+
+	coinbaseBalanceBefore := simState.GetBalance(parent.Coinbase)
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	gasFees := new(big.Int)
+	var totalGasUsed uint64
+
+	for i, encodedTx := range encodedTxs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encodedTx); err != nil {
+			return nil, err
+		}
+
+		simState.SetTxContext(tx.Hash(), i)
+		evm, msg, err := GetEVM(ctx, s.b, simState, header, tx)
+		if err != nil {
+			return nil, err
+		}
+		receipt, _, err := evmcore.ApplyTransactionWithResult(msg, gp, simState, header, tx, &header.GasUsed, evm)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+
+		totalGasUsed += receipt.GasUsed
+		gasPrice, err := tx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+		gasFees.Add(gasFees, new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), gasPrice))
+	}
+
+	coinbaseDiff := new(big.Int).Sub(simState.GetBalance(parent.Coinbase).ToBig(), coinbaseBalanceBefore.ToBig())
+
+	switch score {
+	case scoreGasPrice:
+		if totalGasUsed == 0 {
+			return new(big.Int), nil
+		}
+		return new(big.Int).Div(coinbaseDiff, big.NewInt(int64(totalGasUsed))), nil
+	case scoreEthSentToCoinbase:
+		return new(big.Int).Sub(coinbaseDiff, gasFees), nil
+	default:
+		return coinbaseDiff, nil
+	}
+}
+
+// permutations returns up to max distinct orderings of txs: starting from
+// the identity ordering, it walks the lexicographic permutations of their
+// indices one at a time, stopping once max is reached, so even a large
+// bundle never requires materializing anywhere near its factorial number of
+// orderings.
+func permutations(txs []hexutil.Bytes, max int) [][]hexutil.Bytes {
+	if len(txs) == 0 || max <= 0 {
+		return nil
+	}
+
+	indices := make([]int, len(txs))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	orderings := make([][]hexutil.Bytes, 0, max)
+	orderings = append(orderings, orderingFromIndices(txs, indices))
+	for len(orderings) < max && nextPermutation(indices) {
+		orderings = append(orderings, orderingFromIndices(txs, indices))
+	}
+	return orderings
+}
+
+// orderingFromIndices materializes the tx ordering described by indices.
+func orderingFromIndices(txs []hexutil.Bytes, indices []int) []hexutil.Bytes {
+	ordering := make([]hexutil.Bytes, len(indices))
+	for i, idx := range indices {
+		ordering[i] = txs[idx]
+	}
+	return ordering
+}
+
+// nextPermutation rearranges indices in place into the next lexicographically
+// greater permutation and reports whether one exists; it returns false if
+// indices is already the lexicographically largest permutation (the standard
+// next-permutation algorithm).
+func nextPermutation(indices []int) bool {
+	n := len(indices)
+	i := n - 2
+	for i >= 0 && indices[i] >= indices[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := n - 1
+	for indices[j] <= indices[i] {
+		j--
+	}
+	indices[i], indices[j] = indices[j], indices[i]
+	for l, r := i+1, n-1; l < r; l, r = l+1, r-1 {
+		indices[l], indices[r] = indices[r], indices[l]
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package ethapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestNextPermutationLexicographicOrder(t *testing.T) {
+	indices := []int{0, 1, 2}
+	var got [][]int
+	got = append(got, append([]int(nil), indices...))
+	for nextPermutation(indices) {
+		got = append(got, append([]int(nil), indices...))
+	}
+
+	want := [][]int{
+		{0, 1, 2},
+		{0, 2, 1},
+		{1, 0, 2},
+		{1, 2, 0},
+		{2, 0, 1},
+		{2, 1, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("nextPermutation produced %v, want %v", got, want)
+	}
+}
+
+func TestNextPermutationReturnsFalseOnLastPermutation(t *testing.T) {
+	indices := []int{2, 1, 0}
+	if nextPermutation(indices) {
+		t.Fatalf("expected no permutation after the lexicographically largest one, got %v", indices)
+	}
+}
+
+func TestPermutationsCapsAtMax(t *testing.T) {
+	txs := []hexutil.Bytes{{0x01}, {0x02}, {0x03}, {0x04}}
+
+	orderings := permutations(txs, 5)
+	if len(orderings) != 5 {
+		t.Fatalf("expected permutations to stop at max=5, got %d", len(orderings))
+	}
+
+	seen := make(map[string]bool)
+	for _, ordering := range orderings {
+		key := ""
+		for _, tx := range ordering {
+			key += tx.String() + ","
+		}
+		if seen[key] {
+			t.Fatalf("permutations returned a duplicate ordering: %v", ordering)
+		}
+		seen[key] = true
+	}
+}
+
+func TestPermutationsExceedingFactorialStopsAtAll(t *testing.T) {
+	txs := []hexutil.Bytes{{0x01}, {0x02}, {0x03}}
+
+	orderings := permutations(txs, 100)
+	if len(orderings) != 6 {
+		t.Fatalf("expected all 3! = 6 orderings of 3 txs, got %d", len(orderings))
+	}
+}
+
+func TestPermutationsEmptyInput(t *testing.T) {
+	if orderings := permutations(nil, 10); orderings != nil {
+		t.Fatalf("expected no orderings for an empty tx list, got %v", orderings)
+	}
+}
@@ -34,6 +34,25 @@ import (
 
 // simulateTx performs a simulated execution of the transaction
 func (api *PublicFilterAPI) simulateTx(ctx context.Context, txHash common.Hash) ([]*types.Log, error) {
+	logs, _, err := api.simulateTxWithTracer(ctx, txHash, nil)
+	return logs, err
+}
+
+// SimulateTxWithTracer performs a simulated execution of the transaction like
+// simulateTx, but additionally attaches the named structured tracer
+// ("callTracer", "prestateTracer", "4byteTracer", or a raw JS tracer
+// expression) and returns its result alongside the logs, so callers can
+// inspect the transaction's execution without a second debug_traceTransaction
+// round trip.
+func (api *PublicFilterAPI) SimulateTxWithTracer(ctx context.Context, txHash common.Hash, tracer *string) ([]*types.Log, interface{}, error) {
+	return api.simulateTxWithTracer(ctx, txHash, tracer)
+}
+
+// simulateTxWithTracer is the shared implementation behind simulateTx and
+// SimulateTxWithTracer: it builds a synthetic header on top of the current
+// block, replays txHash against it, and, if tracer is non-nil, attaches the
+// named structured tracer and returns its result alongside the logs.
+func (api *PublicFilterAPI) simulateTxWithTracer(ctx context.Context, txHash common.Hash, tracer *string) ([]*types.Log, interface{}, error) {
 	parent := api.backend.CurrentBlock().EvmHeader
 	blockNumber := new(big.Int).Add(parent.Number, big.NewInt(1))
 
@@ -49,7 +68,7 @@ func (api *PublicFilterAPI) simulateTx(ctx context.Context, txHash common.Hash)
 
 	tx, _, _, err := api.backend.GetTransaction(ctx, txHash)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	header := &evmcore.EvmHeader{
@@ -64,19 +83,30 @@ func (api *PublicFilterAPI) simulateTx(ctx context.Context, txHash common.Hash)
 	gasPool := new(core.GasPool).AddGas(tx.Gas())
 	state, _, err := api.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(parent.Number.Int64())))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	state.SetTxContext(tx.Hash(), 0)
-	evm, msg, err := ethapi.GetEVM(ctx, api.backend, state, header, tx)
+
+	vmConfig, tr, err := ethapi.NewTracerConfig(tracer, tx.Hash(), parent.Hash, header.Number.Uint64())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	evm, msg, err := ethapi.GetEVMWithConfig(ctx, api.backend, state, header, tx, vmConfig)
+	if err != nil {
+		return nil, nil, err
 	}
 	receipt, _, err := evmcore.ApplyTransactionWithResult(msg, gasPool, state, header, tx, &header.GasUsed, evm)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if tracer == nil {
+		return receipt.Logs, nil, nil
 	}
 
-	// Process and print logs (events)
-	// log.Info("New Captured event: %+v\n", receipt.Logs)
-	return receipt.Logs, nil
+	trace, err := ethapi.TraceResult(tr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt.Logs, trace, nil
 }